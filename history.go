@@ -0,0 +1,230 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const historyDateLayout = "2006-01-02 15:04:05"
+
+// HistoryStore persists observed trade halts to a local SQLite database so
+// they can be queried after the fact, across restarts.
+type HistoryStore struct {
+	db *sql.DB
+}
+
+// OpenHistoryStore opens (creating if necessary) the SQLite database at path
+// and ensures the halts table exists.
+func OpenHistoryStore(path string) (*HistoryStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history store: %w", err)
+	}
+
+	const schema = `
+CREATE TABLE IF NOT EXISTS halts (
+	symbol TEXT NOT NULL,
+	name TEXT NOT NULL,
+	exchange TEXT NOT NULL,
+	reason TEXT NOT NULL,
+	normalized_reason TEXT NOT NULL,
+	halt_time DATETIME NOT NULL,
+	resume_time DATETIME,
+	PRIMARY KEY (symbol, halt_time)
+);
+`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to init history store: %w", err)
+	}
+
+	return &HistoryStore{db: db}, nil
+}
+
+func (s *HistoryStore) Close() error {
+	return s.db.Close()
+}
+
+// Save upserts halts into the store, keyed by (Symbol, HaltDateTime).
+func (s *HistoryStore) Save(halts []TradeHalt) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin history transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+INSERT INTO halts (symbol, name, exchange, reason, normalized_reason, halt_time, resume_time)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (symbol, halt_time) DO UPDATE SET resume_time = excluded.resume_time
+`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare history insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, halt := range halts {
+		var resumeTime any
+		if !halt.ResumeDateTime.IsZero() {
+			resumeTime = halt.ResumeDateTime.UTC()
+		}
+
+		// Normalize to UTC before binding: the sqlite driver serializes
+		// time.Time via String(), which renders the zone, so the same
+		// instant parsed against different zones (wall-clock vs RFC3339)
+		// would otherwise produce different halt_time values and defeat
+		// the PRIMARY KEY (symbol, halt_time) upsert.
+		if _, err := stmt.Exec(halt.Symbol, halt.Name, halt.Exchange, halt.Reason, string(halt.NormalizedReason), halt.HaltDateTime.UTC(), resumeTime); err != nil {
+			return fmt.Errorf("failed to save halt for %s: %w", halt.Symbol, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// HistoryQuery filters halts returned by HistoryStore.Query. Zero values are
+// treated as unset. Reason filters on the normalized reason (e.g.
+// "volatility"), not the raw per-exchange code, so it behaves the same
+// across NYSE/Nasdaq/OTC halts.
+type HistoryQuery struct {
+	From   time.Time
+	To     time.Time
+	Symbol string
+	Reason HaltReason
+}
+
+func (s *HistoryStore) Query(q HistoryQuery) ([]TradeHalt, error) {
+	sqlQuery := "SELECT symbol, name, exchange, reason, normalized_reason, halt_time, resume_time FROM halts WHERE 1 = 1"
+	var args []any
+
+	if !q.From.IsZero() {
+		sqlQuery += " AND halt_time >= ?"
+		args = append(args, q.From.UTC())
+	}
+	if !q.To.IsZero() {
+		sqlQuery += " AND halt_time <= ?"
+		args = append(args, q.To.UTC())
+	}
+	if q.Symbol != "" {
+		sqlQuery += " AND symbol = ?"
+		args = append(args, q.Symbol)
+	}
+	if q.Reason != "" {
+		sqlQuery += " AND normalized_reason = ?"
+		args = append(args, string(q.Reason))
+	}
+	sqlQuery += " ORDER BY halt_time"
+
+	rows, err := s.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var halts []TradeHalt
+	for rows.Next() {
+		var halt TradeHalt
+		var normalizedReason string
+		var resumeTime sql.NullTime
+
+		if err := rows.Scan(&halt.Symbol, &halt.Name, &halt.Exchange, &halt.Reason, &normalizedReason, &halt.HaltDateTime, &resumeTime); err != nil {
+			return nil, fmt.Errorf("failed to scan history row: %w", err)
+		}
+		halt.NormalizedReason = HaltReason(normalizedReason)
+		if resumeTime.Valid {
+			halt.ResumeDateTime = resumeTime.Time
+		}
+
+		halts = append(halts, halt)
+	}
+
+	return halts, rows.Err()
+}
+
+type HistoryCmd struct {
+	DB     string `help:"Path to the SQLite history database." default:"halts.db"`
+	From   string `help:"Only include halts on or after this time (2006-01-02 15:04:05, NYSE local time)."`
+	To     string `help:"Only include halts on or before this time (2006-01-02 15:04:05, NYSE local time)."`
+	Symbol string `help:"Only include halts for this symbol."`
+	Reason string `help:"Only include halts with this normalized reason (volatility, news_pending, news_released, regulatory, other)."`
+	Format string `help:"Output format: table, json, or csv." default:"table" enum:"table,json,csv"`
+}
+
+func (h *HistoryCmd) Run() error {
+	store, err := OpenHistoryStore(h.DB)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	query := HistoryQuery{
+		Symbol: strings.ToUpper(h.Symbol),
+		Reason: HaltReason(h.Reason),
+	}
+
+	if h.From != "" {
+		query.From, err = time.ParseInLocation(historyDateLayout, h.From, nyseLocation)
+		if err != nil {
+			return fmt.Errorf("failed to parse --from: %w", err)
+		}
+	}
+	if h.To != "" {
+		query.To, err = time.ParseInLocation(historyDateLayout, h.To, nyseLocation)
+		if err != nil {
+			return fmt.Errorf("failed to parse --to: %w", err)
+		}
+	}
+
+	halts, err := store.Query(query)
+	if err != nil {
+		return err
+	}
+
+	switch h.Format {
+	case "json":
+		return displayHaltsJSON(halts)
+	case "csv":
+		return displayHaltsCSV(halts)
+	default:
+		displayHaltsTable(halts)
+		return nil
+	}
+}
+
+func displayHaltsJSON(halts []TradeHalt) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(halts)
+}
+
+func displayHaltsCSV(halts []TradeHalt) error {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if err := w.Write([]string{"symbol", "name", "exchange", "reason", "halt_time", "resume_time"}); err != nil {
+		return err
+	}
+
+	for _, halt := range halts {
+		var haltTime, resumeTime string
+		if !halt.HaltDateTime.IsZero() {
+			haltTime = halt.HaltDateTime.Local().Format(historyDateLayout)
+		}
+		if !halt.ResumeDateTime.IsZero() {
+			resumeTime = halt.ResumeDateTime.Local().Format(historyDateLayout)
+		}
+
+		if err := w.Write([]string{halt.Symbol, halt.Name, halt.Exchange, halt.Reason, haltTime, resumeTime}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
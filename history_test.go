@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryStoreQueryFiltersByNormalizedReason(t *testing.T) {
+	store, err := OpenHistoryStore(filepath.Join(t.TempDir(), "halts.db"))
+	if err != nil {
+		t.Fatalf("OpenHistoryStore() error = %v", err)
+	}
+	defer store.Close()
+
+	haltTime := time.Date(2024, 1, 2, 9, 30, 0, 0, nyseLocation)
+
+	halts := []TradeHalt{
+		{Symbol: "ABC", Exchange: "NYSE", Reason: "LUDP", NormalizedReason: ReasonVolatility, HaltDateTime: haltTime},
+		{Symbol: "DEF", Exchange: "NASDAQ", Reason: "MWC1", NormalizedReason: ReasonVolatility, HaltDateTime: haltTime.Add(time.Minute)},
+		{Symbol: "GHI", Exchange: "OTC", Reason: "D", NormalizedReason: ReasonRegulatory, HaltDateTime: haltTime.Add(2 * time.Minute)},
+	}
+
+	if err := store.Save(halts); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Query(HistoryQuery{Reason: ReasonVolatility})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("len(got) = %d, want 2 (got %+v)", len(got), got)
+	}
+	for _, halt := range got {
+		if halt.NormalizedReason != ReasonVolatility {
+			t.Errorf("halt %q has normalized reason %q, want %q", halt.Symbol, halt.NormalizedReason, ReasonVolatility)
+		}
+	}
+
+	// Querying by the raw, per-exchange reason code should not match -
+	// Reason filters on the normalized enum, not the raw code.
+	rawMatches, err := store.Query(HistoryQuery{Reason: "LUDP"})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(rawMatches) != 0 {
+		t.Errorf("len(rawMatches) = %d, want 0", len(rawMatches))
+	}
+}
+
+func TestHistoryStoreQueryFiltersBySymbolAndTimeRange(t *testing.T) {
+	store, err := OpenHistoryStore(filepath.Join(t.TempDir(), "halts.db"))
+	if err != nil {
+		t.Fatalf("OpenHistoryStore() error = %v", err)
+	}
+	defer store.Close()
+
+	base := time.Date(2024, 1, 2, 9, 30, 0, 0, nyseLocation)
+
+	halts := []TradeHalt{
+		{Symbol: "ABC", Exchange: "NYSE", NormalizedReason: ReasonOther, HaltDateTime: base},
+		{Symbol: "ABC", Exchange: "NYSE", NormalizedReason: ReasonOther, HaltDateTime: base.Add(24 * time.Hour)},
+		{Symbol: "XYZ", Exchange: "NYSE", NormalizedReason: ReasonOther, HaltDateTime: base},
+	}
+
+	if err := store.Save(halts); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Query(HistoryQuery{
+		Symbol: "ABC",
+		From:   base.Add(-time.Minute),
+		To:     base.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+
+	if len(got) != 1 || !got[0].HaltDateTime.Equal(base) {
+		t.Fatalf("got %+v, want a single halt at %v", got, base)
+	}
+}
@@ -1,26 +1,31 @@
 package main
 
 import (
-	"encoding/csv"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
-	"strconv"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/alecthomas/kong"
 )
 
+const bellSound = "\a"
+
 const (
-	nyseTradeHaltURL = "https://www.nyse.com/api/trade-halts/current/download"
-	bellSound        = "\a"
+	backoffInitial = 1 * time.Second
+	backoffMax     = 1 * time.Minute
 )
 
 var nyseLocation *time.Location
 
+// slogLogger is the structured JSON logger used throughout the program in
+// place of the standard log package, so halt events can be consumed by log
+// aggregators alongside the Prometheus metrics.
+var slogLogger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
 func init() {
 	var err error
 	nyseLocation, err = time.LoadLocation("America/New_York")
@@ -30,14 +35,24 @@ func init() {
 }
 
 type CLI struct {
-	Fetch FetchCmd `cmd:"" help:"Fetch current NYSE trade halts."`
-	Watch WatchCmd `cmd:"" help:"Watch for new NYSE trade halts and ding on new halts."`
+	Fetch   FetchCmd   `cmd:"" help:"Fetch current NYSE trade halts."`
+	Watch   WatchCmd   `cmd:"" help:"Watch for new NYSE trade halts and ding on new halts."`
+	History HistoryCmd `cmd:"" help:"Query previously observed trade halts from the history database."`
+	Serve   ServeCmd   `cmd:"" help:"Serve trade halts over SSE, WebSocket, and REST for downstream consumers."`
 }
 
-type FetchCmd struct{}
+type FetchCmd struct {
+	Exchange  string `help:"Comma-separated list of exchanges to query (nyse,nasdaq,otc)." default:"nyse"`
+	UserAgent string `help:"User-Agent header to send with requests." default:"nyse-trade-halts/1.0"`
+}
 
 func (f *FetchCmd) Run() error {
-	halts, _, err := fetchTradeHalts()
+	sources, err := haltSourcesFor(strings.Split(f.Exchange, ","), newHTTPClient(), f.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	halts, _, err := fetchFromSources(sources)
 	if err != nil {
 		return fmt.Errorf("failed to fetch trade halts: %w", err)
 	}
@@ -47,10 +62,63 @@ func (f *FetchCmd) Run() error {
 }
 
 type WatchCmd struct {
-	Interval time.Duration `help:"Polling interval (e.g., 5s, 1m)." default:"5s"`
+	Interval  time.Duration `help:"Polling interval (e.g., 5s, 1m)." default:"5s"`
+	Exchange  string        `help:"Comma-separated list of exchanges to query (nyse,nasdaq,otc)." default:"nyse"`
+	UserAgent string        `help:"User-Agent header to send with requests." default:"nyse-trade-halts/1.0"`
+	History   string        `help:"Path to a SQLite database to persist observed halts to. Pass an empty string to disable." default:"halts.db"`
+
+	WebhookURL     string `help:"POST new halt/resume events as JSON to this URL."`
+	SlackWebhook   string `help:"POST new halt/resume events to this Slack incoming webhook URL."`
+	DiscordWebhook string `help:"POST new halt/resume events to this Discord webhook URL."`
+	Exec           string `help:"Run this shell command on new halt/resume events, with halt fields passed as HALT_* env vars."`
+
+	FilterReason string `help:"Only notify for halts whose normalized reason (volatility, news_pending, news_released, regulatory, other) matches this regexp."`
+	FilterSymbol string `help:"Only notify for halts whose symbol matches this regexp."`
+
+	MetricsAddr string `help:"Address to serve Prometheus metrics on (e.g., :9090). Disabled if unset."`
 }
 
 func (w *WatchCmd) Run() error {
+	sources, err := haltSourcesFor(strings.Split(w.Exchange, ","), newHTTPClient(), w.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	var store *HistoryStore
+	if w.History != "" {
+		var err error
+		store, err = OpenHistoryStore(w.History)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+	}
+
+	filter, err := newHaltFilter(w.FilterReason, w.FilterSymbol)
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	var notifiers []Notifier
+	if w.WebhookURL != "" {
+		notifiers = append(notifiers, &WebhookNotifier{URL: w.WebhookURL, Client: httpClient})
+	}
+	if w.SlackWebhook != "" {
+		notifiers = append(notifiers, &SlackNotifier{URL: w.SlackWebhook, Client: httpClient})
+	}
+	if w.DiscordWebhook != "" {
+		notifiers = append(notifiers, &DiscordNotifier{URL: w.DiscordWebhook, Client: httpClient})
+	}
+	if w.Exec != "" {
+		notifiers = append(notifiers, &ExecNotifier{Cmd: w.Exec})
+	}
+
+	if w.MetricsAddr != "" {
+		metricsServer := serveMetrics(w.MetricsAddr)
+		defer metricsServer.Close()
+	}
+
 	displayFunc := func(halts []TradeHalt, lastModified *time.Time) {
 		clearScreen()
 		displayHaltsTable(halts)
@@ -68,9 +136,13 @@ func (w *WatchCmd) Run() error {
 	defer ticker.Stop()
 
 	for {
-		currentHalts, lastModified, err := fetchTradeHalts()
-		if err != nil {
-			log.Fatal(err)
+		currentHalts, lastModified := fetchWithBackoff(sources)
+		recordSnapshot(currentHalts, lastModified)
+
+		if store != nil {
+			if err := store.Save(currentHalts); err != nil {
+				slogLogger.Error("failed to save halts to history", "error", err)
+			}
 		}
 
 		haltsUpdated := false
@@ -82,6 +154,12 @@ func (w *WatchCmd) Run() error {
 					// Resume time updated
 					prevHalts[halt.Symbol] = halt
 					haltsUpdated = true
+
+					slogLogger.Info("halt updated", "event", "resume_updated", "symbol", halt.Symbol, "reason", halt.Reason)
+
+					if filter.matches(halt) {
+						notifyAll(notifiers, NotificationEvent{Type: "resume_updated", Halt: halt})
+					}
 				}
 
 				continue
@@ -90,6 +168,13 @@ func (w *WatchCmd) Run() error {
 			// New halt added
 			prevHalts[halt.Symbol] = halt
 			haltsUpdated = true
+			recordNewHalt(halt)
+
+			slogLogger.Info("new halt", "event", "new_halt", "symbol", halt.Symbol, "reason", halt.Reason)
+
+			if filter.matches(halt) {
+				notifyAll(notifiers, NotificationEvent{Type: "new_halt", Halt: halt})
+			}
 		}
 
 		if haltsUpdated {
@@ -106,6 +191,31 @@ func (w *WatchCmd) Run() error {
 	return nil
 }
 
+// fetchWithBackoff fetches from sources, retrying with exponential backoff
+// on network/server errors instead of giving up, since WatchCmd is meant to
+// run unattended for long stretches.
+func fetchWithBackoff(sources []HaltSource) ([]TradeHalt, *time.Time) {
+	delay := backoffInitial
+
+	for {
+		start := time.Now()
+		halts, lastModified, err := fetchFromSources(sources)
+		recordFetch(time.Since(start), err)
+
+		if err == nil {
+			return halts, lastModified
+		}
+
+		slogLogger.Error("fetch failed, retrying", "delay", delay.String(), "error", err)
+		time.Sleep(delay)
+
+		delay *= 2
+		if delay > backoffMax {
+			delay = backoffMax
+		}
+	}
+}
+
 func displayHaltsTable(halts []TradeHalt) {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintln(w, "SYMBOL\tNAME\tEXCHANGE\tREASON\tHALT TIME (LOCAL)\tRESUME TIME (LOCAL)")
@@ -133,95 +243,14 @@ func clearScreen() {
 	fmt.Print("\033[2J\033[H")
 }
 
-func tryUnquote(s string) string {
-	unquoted, err := strconv.Unquote(s)
-	if err != nil {
-		return s
-	}
-	return unquoted
-}
-
 type TradeHalt struct {
-	Symbol         string
-	Name           string
-	Exchange       string
-	Reason         string
-	HaltDateTime   time.Time
-	ResumeDateTime time.Time
-}
-
-func parseTradeHalts(reader io.Reader) ([]TradeHalt, error) {
-	csvReader := csv.NewReader(reader)
-	records, err := csvReader.ReadAll()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read csv: %w", err)
-	}
-
-	if len(records) < 2 {
-		return []TradeHalt{}, nil
-	}
-
-	var halts []TradeHalt
-
-	for i, record := range records {
-		if i == 0 {
-			continue
-		}
-		if len(record) != 8 {
-			panic("malformed record")
-		}
-
-		var haltDateTime time.Time
-		if record[0] != "" && record[1] != "" {
-			haltDateTime, err = time.ParseInLocation("2006-01-02 15:04:05", record[0]+" "+record[1], nyseLocation)
-			if err != nil {
-				log.Printf("failed to parse halt datetime for %s: %v", record[2], err)
-			}
-		}
-
-		var resumeDateTime time.Time
-		if record[6] != "" && record[7] != "" {
-			resumeDateTime, err = time.ParseInLocation("2006-01-02 15:04:05", record[6]+" "+record[7], nyseLocation)
-			if err != nil {
-				log.Printf("failed to parse resume datetime for %s: %v", record[2], err)
-			}
-		}
-
-		halts = append(halts, TradeHalt{
-			Symbol:         record[2],
-			Name:           tryUnquote(record[3]),
-			Exchange:       record[4],
-			Reason:         record[5],
-			HaltDateTime:   haltDateTime,
-			ResumeDateTime: resumeDateTime,
-		})
-	}
-
-	return halts, nil
-}
-
-func fetchTradeHalts() ([]TradeHalt, *time.Time, error) {
-	resp, err := http.Get(nyseTradeHaltURL)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to fetch trade halts: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
-	}
-
-	halts, err := parseTradeHalts(resp.Body)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse trade halts: %w", err)
-	}
-
-	lastModified, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse last modified header: %w", err)
-	}
-
-	return halts, &lastModified, nil
+	Symbol           string
+	Name             string
+	Exchange         string
+	Reason           string
+	NormalizedReason HaltReason
+	HaltDateTime     time.Time
+	ResumeDateTime   time.Time
 }
 
 func main() {
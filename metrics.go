@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	haltsCurrentGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nyse_halts_current",
+		Help: "Number of trade halts currently in effect.",
+	})
+
+	haltsTotalCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nyse_halts_total",
+		Help: "Total number of trade halts observed, by normalized reason.",
+	}, []string{"reason"})
+
+	fetchDurationHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "nyse_fetch_duration_seconds",
+		Help:    "Time taken to fetch and merge halts from all configured sources.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	fetchErrorsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "nyse_fetch_errors_total",
+		Help: "Total number of failed fetch attempts.",
+	})
+
+	lastModifiedGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nyse_last_modified_timestamp_seconds",
+		Help: "Unix timestamp of the most recent Last-Modified time across sources.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(
+		haltsCurrentGauge,
+		haltsTotalCounter,
+		fetchDurationHistogram,
+		fetchErrorsCounter,
+		lastModifiedGauge,
+	)
+}
+
+// serveMetrics starts an HTTP server exposing Prometheus metrics at /metrics
+// on addr. The caller is responsible for shutting it down.
+func serveMetrics(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slogLogger.Error("metrics server failed", "error", err)
+		}
+	}()
+
+	return srv
+}
+
+// recordFetch updates fetch-level metrics for a single fetch attempt.
+func recordFetch(duration time.Duration, err error) {
+	fetchDurationHistogram.Observe(duration.Seconds())
+	if err != nil {
+		fetchErrorsCounter.Inc()
+	}
+}
+
+// recordSnapshot updates metrics that reflect the current set of halts.
+func recordSnapshot(halts []TradeHalt, lastModified *time.Time) {
+	haltsCurrentGauge.Set(float64(len(halts)))
+	if lastModified != nil {
+		lastModifiedGauge.Set(float64(lastModified.Unix()))
+	}
+}
+
+// recordNewHalt increments the halts-by-reason counter for a newly observed
+// halt.
+func recordNewHalt(halt TradeHalt) {
+	haltsTotalCounter.WithLabelValues(string(halt.NormalizedReason)).Inc()
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"time"
+)
+
+// NotificationEvent describes a change to a TradeHalt that notifiers should
+// be told about.
+type NotificationEvent struct {
+	Type string // "new_halt" or "resume_updated"
+	Halt TradeHalt
+}
+
+// Notifier pushes a NotificationEvent to some external sink.
+type Notifier interface {
+	Notify(ctx context.Context, event NotificationEvent) error
+}
+
+const (
+	notifyMaxRetries = 3
+	notifyBaseDelay  = 500 * time.Millisecond
+)
+
+// notifyAll dispatches event to every notifier concurrently, retrying each
+// with exponential backoff. Failures are logged, not returned, so a single
+// broken sink can't block the others or the watch loop.
+func notifyAll(notifiers []Notifier, event NotificationEvent) {
+	for _, n := range notifiers {
+		go func(n Notifier) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			var err error
+			for attempt := 0; attempt < notifyMaxRetries; attempt++ {
+				if attempt > 0 {
+					time.Sleep(notifyBaseDelay << (attempt - 1))
+				}
+
+				if err = n.Notify(ctx, event); err == nil {
+					return
+				}
+			}
+
+			slogLogger.Error("notifier failed", "attempts", notifyMaxRetries, "symbol", event.Halt.Symbol, "error", err)
+		}(n)
+	}
+}
+
+// WebhookNotifier POSTs the event as JSON to an arbitrary URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, event NotificationEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return postJSON(ctx, n.Client, n.URL, body)
+}
+
+// SlackNotifier posts the event as a Slack incoming webhook message.
+type SlackNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, event NotificationEvent) error {
+	body, err := json.Marshal(map[string]string{
+		"text": formatNotificationText(event),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack payload: %w", err)
+	}
+
+	return postJSON(ctx, n.Client, n.URL, body)
+}
+
+// DiscordNotifier posts the event as a Discord webhook message.
+type DiscordNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n *DiscordNotifier) Notify(ctx context.Context, event NotificationEvent) error {
+	body, err := json.Marshal(map[string]string{
+		"content": formatNotificationText(event),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal discord payload: %w", err)
+	}
+
+	return postJSON(ctx, n.Client, n.URL, body)
+}
+
+// ExecNotifier runs an external command with the halt's fields passed as
+// environment variables.
+type ExecNotifier struct {
+	Cmd string
+}
+
+func (n *ExecNotifier) Notify(ctx context.Context, event NotificationEvent) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", n.Cmd)
+	cmd.Env = append(os.Environ(),
+		"HALT_EVENT_TYPE="+event.Type,
+		"HALT_SYMBOL="+event.Halt.Symbol,
+		"HALT_NAME="+event.Halt.Name,
+		"HALT_EXCHANGE="+event.Halt.Exchange,
+		"HALT_REASON="+event.Halt.Reason,
+		"HALT_TIME="+event.Halt.HaltDateTime.Format(time.RFC3339),
+		"HALT_RESUME_TIME="+event.Halt.ResumeDateTime.Format(time.RFC3339),
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run exec notifier: %w", err)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func formatNotificationText(event NotificationEvent) string {
+	switch event.Type {
+	case "resume_updated":
+		return fmt.Sprintf("%s resume time updated: %s (%s)", event.Halt.Symbol, event.Halt.ResumeDateTime.Local().Format(time.RFC1123Z), event.Halt.Reason)
+	default:
+		return fmt.Sprintf("%s halted: %s (%s)", event.Halt.Symbol, event.Halt.Reason, event.Halt.Name)
+	}
+}
+
+// haltFilter decides whether a halt should be surfaced to notifiers, based on
+// optional regexes over the reason and symbol fields.
+type haltFilter struct {
+	reason *regexp.Regexp
+	symbol *regexp.Regexp
+}
+
+func newHaltFilter(reasonPattern, symbolPattern string) (*haltFilter, error) {
+	f := &haltFilter{}
+
+	if reasonPattern != "" {
+		re, err := regexp.Compile(reasonPattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile --filter-reason: %w", err)
+		}
+		f.reason = re
+	}
+
+	if symbolPattern != "" {
+		re, err := regexp.Compile(symbolPattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile --filter-symbol: %w", err)
+		}
+		f.symbol = re
+	}
+
+	return f, nil
+}
+
+func (f *haltFilter) matches(halt TradeHalt) bool {
+	if f.reason != nil && !f.reason.MatchString(string(halt.NormalizedReason)) {
+		return false
+	}
+	if f.symbol != nil && !f.symbol.MatchString(halt.Symbol) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// haltEvent is the message published to SSE/WebSocket subscribers: the full
+// TradeHalt plus what kind of change it represents.
+type haltEvent struct {
+	EventType string `json:"event_type"` // "new", "resume_updated", "cleared"
+	TradeHalt
+}
+
+type ServeCmd struct {
+	Addr      string        `help:"Address to serve HTTP on." default:":8080"`
+	Interval  time.Duration `help:"Polling interval (e.g., 5s, 1m)." default:"5s"`
+	Exchange  string        `help:"Comma-separated list of exchanges to query (nyse,nasdaq,otc)." default:"nyse"`
+	UserAgent string        `help:"User-Agent header to send with requests." default:"nyse-trade-halts/1.0"`
+}
+
+func (s *ServeCmd) Run() error {
+	sources, err := haltSourcesFor(strings.Split(s.Exchange, ","), newHTTPClient(), s.UserAgent)
+	if err != nil {
+		return err
+	}
+
+	hub := newEventHub()
+	snapshot := newHaltSnapshot()
+
+	go s.poll(sources, hub, snapshot)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", hub.serveSSE)
+	mux.HandleFunc("/ws", hub.serveWS)
+	mux.HandleFunc("/halts", snapshot.serveHTTP)
+
+	slogLogger.Info("serving", "addr", s.Addr)
+	return http.ListenAndServe(s.Addr, mux)
+}
+
+// poll fetches halts on Interval, publishing a haltEvent to hub for every
+// new halt, resume-time update, or halt that has dropped out of the feed.
+func (s *ServeCmd) poll(sources []HaltSource, hub *eventHub, snapshot *haltSnapshot) {
+	prevHalts := make(map[string]TradeHalt)
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		currentHalts, _ := fetchWithBackoff(sources)
+		currentBySymbol := make(map[string]TradeHalt, len(currentHalts))
+
+		for _, halt := range currentHalts {
+			currentBySymbol[halt.Symbol] = halt
+
+			prevHalt, ok := prevHalts[halt.Symbol]
+			switch {
+			case !ok:
+				hub.publish(haltEvent{EventType: "new", TradeHalt: halt})
+			case prevHalt.ResumeDateTime != halt.ResumeDateTime:
+				hub.publish(haltEvent{EventType: "resume_updated", TradeHalt: halt})
+			}
+		}
+
+		for symbol, halt := range prevHalts {
+			if _, ok := currentBySymbol[symbol]; !ok {
+				hub.publish(haltEvent{EventType: "cleared", TradeHalt: halt})
+			}
+		}
+
+		prevHalts = currentBySymbol
+		snapshot.set(currentHalts)
+
+		<-ticker.C
+	}
+}
+
+// haltSnapshot holds the most recently fetched set of halts for the /halts
+// REST endpoint.
+type haltSnapshot struct {
+	mu    sync.RWMutex
+	halts []TradeHalt
+}
+
+func newHaltSnapshot() *haltSnapshot {
+	return &haltSnapshot{}
+}
+
+func (s *haltSnapshot) set(halts []TradeHalt) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.halts = halts
+}
+
+func (s *haltSnapshot) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.halts); err != nil {
+		slogLogger.Error("failed to write halts snapshot", "error", err)
+	}
+}
+
+// eventHub fans out halt events to any number of SSE/WebSocket subscribers.
+type eventHub struct {
+	mu      sync.Mutex
+	clients map[chan []byte]bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{clients: make(map[chan []byte]bool)}
+}
+
+func (h *eventHub) subscribe() chan []byte {
+	ch := make(chan []byte, 16)
+
+	h.mu.Lock()
+	h.clients[ch] = true
+	h.mu.Unlock()
+
+	return ch
+}
+
+func (h *eventHub) unsubscribe(ch chan []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[ch]; ok {
+		delete(h.clients, ch)
+		close(ch)
+	}
+}
+
+func (h *eventHub) publish(event haltEvent) {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		slogLogger.Error("failed to marshal halt event", "error", err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.clients {
+		select {
+		case ch <- msg:
+		default:
+			// Slow subscriber, drop the event rather than block the poller.
+		}
+	}
+}
+
+func (h *eventHub) serveSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := h.subscribe()
+	defer h.unsubscribe(ch)
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", msg)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+func (h *eventHub) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		slogLogger.Error("websocket upgrade failed", "error", err)
+		return
+	}
+	defer conn.Close()
+
+	ch := h.subscribe()
+	defer h.unsubscribe(ch)
+
+	// A dead peer (TCP drop, no close frame) otherwise never surfaces as a
+	// write error if no event is published afterward, leaking the
+	// subscription forever. Read in the background so pong timeouts and
+	// close frames are noticed, and ping periodically to detect silent
+	// peers proactively.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			return nil
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	nyseTradeHaltURL   = "https://www.nyse.com/api/trade-halts/current/download"
+	nasdaqTradeHaltURL = "https://www.nasdaqtrader.com/rss.aspx?feed=tradehalts"
+	otcTradeHaltURL    = "https://otce.finra.org/otcapi/halts/current"
+)
+
+// newHTTPClient builds a client suited to frequent polling: a sane timeout
+// and a transport that reuses connections instead of dialing fresh ones on
+// every poll.
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// conditionalGet issues a GET against url, attaching If-None-Match/
+// If-Modified-Since headers when etag/lastModified are set, so the server
+// can reply 304 Not Modified instead of resending the body.
+func conditionalGet(client *http.Client, url, userAgent, etag string, lastModified time.Time) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if !lastModified.IsZero() {
+		req.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// HaltReason is a normalized reason code, shared across exchanges so that
+// notification filters and history queries don't need to know each source's
+// own vocabulary.
+type HaltReason string
+
+const (
+	ReasonVolatility   HaltReason = "volatility"
+	ReasonNewsPending  HaltReason = "news_pending"
+	ReasonNewsReleased HaltReason = "news_released"
+	ReasonRegulatory   HaltReason = "regulatory"
+	ReasonOther        HaltReason = "other"
+)
+
+// HaltSource fetches the current set of trade halts from a single exchange
+// or feed.
+type HaltSource interface {
+	// Name identifies the source, e.g. "nyse", "nasdaq", "otc".
+	Name() string
+	Fetch() ([]TradeHalt, *time.Time, error)
+}
+
+func haltSourcesFor(exchanges []string, client *http.Client, userAgent string) ([]HaltSource, error) {
+	var sources []HaltSource
+
+	for _, exchange := range exchanges {
+		switch strings.ToLower(strings.TrimSpace(exchange)) {
+		case "nyse":
+			sources = append(sources, &NYSESource{Client: client, UserAgent: userAgent})
+		case "nasdaq":
+			sources = append(sources, &NasdaqSource{Client: client, UserAgent: userAgent})
+		case "otc":
+			sources = append(sources, &OTCSource{Client: client, UserAgent: userAgent})
+		default:
+			return nil, fmt.Errorf("unknown exchange %q", exchange)
+		}
+	}
+
+	return sources, nil
+}
+
+// fetchFromSources fetches halts from every source concurrently, then merges
+// the results, deduping by (Symbol, HaltDateTime) and keeping the most
+// recent Last-Modified time across sources.
+func fetchFromSources(sources []HaltSource) ([]TradeHalt, *time.Time, error) {
+	type result struct {
+		source       string
+		halts        []TradeHalt
+		lastModified *time.Time
+		err          error
+	}
+
+	results := make([]result, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source HaltSource) {
+			defer wg.Done()
+			halts, lastModified, err := source.Fetch()
+			results[i] = result{source: source.Name(), halts: halts, lastModified: lastModified, err: err}
+		}(i, source)
+	}
+	wg.Wait()
+
+	type dedupKey struct {
+		symbol   string
+		haltUnix int64
+	}
+	seen := make(map[dedupKey]bool)
+	var merged []TradeHalt
+	var lastModified *time.Time
+
+	for _, r := range results {
+		if r.err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch from %s: %w", r.source, r.err)
+		}
+
+		for _, halt := range r.halts {
+			// Key on the instant, not time.Time.String(): sources parse
+			// timestamps against different zones (nyseLocation vs. the
+			// explicit offset in an RFC3339 string), so two time.Time
+			// values for the same instant render as different strings
+			// even though Equal() is true.
+			key := dedupKey{symbol: halt.Symbol, haltUnix: halt.HaltDateTime.UTC().UnixNano()}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, halt)
+		}
+
+		if r.lastModified != nil && (lastModified == nil || r.lastModified.After(*lastModified)) {
+			lastModified = r.lastModified
+		}
+	}
+
+	return merged, lastModified, nil
+}
+
+// NYSESource fetches trade halts from the NYSE CSV download endpoint. It
+// remembers the ETag/Last-Modified of the last successful fetch so repeated
+// polls can use conditional GETs.
+type NYSESource struct {
+	Client    *http.Client
+	UserAgent string
+
+	etag         string
+	lastModified time.Time
+	cachedHalts  []TradeHalt
+}
+
+func (s *NYSESource) Name() string { return "nyse" }
+
+func (s *NYSESource) Fetch() ([]TradeHalt, *time.Time, error) {
+	resp, err := conditionalGet(s.Client, nyseTradeHaltURL, s.UserAgent, s.etag, s.lastModified)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch trade halts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return s.cachedHalts, &s.lastModified, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	halts, err := parseNYSETradeHalts(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse trade halts: %w", err)
+	}
+
+	lastModified, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse last modified header: %w", err)
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.lastModified = lastModified
+	s.cachedHalts = halts
+
+	return halts, &s.lastModified, nil
+}
+
+func tryUnquote(s string) string {
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return s
+	}
+	return unquoted
+}
+
+func parseNYSETradeHalts(reader io.Reader) ([]TradeHalt, error) {
+	csvReader := csv.NewReader(reader)
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	if len(records) < 2 {
+		return []TradeHalt{}, nil
+	}
+
+	var halts []TradeHalt
+
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		if len(record) != 8 {
+			panic("malformed record")
+		}
+
+		var haltDateTime time.Time
+		if record[0] != "" && record[1] != "" {
+			haltDateTime, err = time.ParseInLocation("2006-01-02 15:04:05", record[0]+" "+record[1], nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse halt datetime", "symbol", record[2], "error", err)
+			}
+		}
+
+		var resumeDateTime time.Time
+		if record[6] != "" && record[7] != "" {
+			resumeDateTime, err = time.ParseInLocation("2006-01-02 15:04:05", record[6]+" "+record[7], nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse resume datetime", "symbol", record[2], "error", err)
+			}
+		}
+
+		reason := record[5]
+
+		halts = append(halts, TradeHalt{
+			Symbol:           record[2],
+			Name:             tryUnquote(record[3]),
+			Exchange:         record[4],
+			Reason:           reason,
+			NormalizedReason: normalizeNYSEReason(reason),
+			HaltDateTime:     haltDateTime,
+			ResumeDateTime:   resumeDateTime,
+		})
+	}
+
+	return halts, nil
+}
+
+func normalizeNYSEReason(code string) HaltReason {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "LUDP", "LUDS", "LUDN", "MWC1", "MWC2", "MWC3":
+		return ReasonVolatility
+	case "T1", "T2", "T6", "T8", "T12":
+		return ReasonNewsPending
+	case "T3":
+		return ReasonNewsReleased
+	case "H10", "H11", "H4", "H9", "D":
+		return ReasonRegulatory
+	default:
+		return ReasonOther
+	}
+}
+
+// NasdaqSource fetches trade halts from the Nasdaq Trader RSS feed.
+type NasdaqSource struct {
+	Client    *http.Client
+	UserAgent string
+
+	etag         string
+	lastModified time.Time
+	cachedHalts  []TradeHalt
+}
+
+func (s *NasdaqSource) Name() string { return "nasdaq" }
+
+type nasdaqRSS struct {
+	Channel struct {
+		LastBuildDate string          `xml:"lastBuildDate"`
+		Items         []nasdaqRSSHalt `xml:"item"`
+	} `xml:"channel"`
+}
+
+// nasdaqRSSHalt mirrors the flattened halt fields Nasdaq includes on each RSS
+// item's description, exposed here as their own elements for convenience.
+type nasdaqRSSHalt struct {
+	Symbol         string `xml:"IssueSymbol"`
+	Name           string `xml:"IssueName"`
+	Market         string `xml:"MarketCategory"`
+	ReasonCode     string `xml:"ReasonCode"`
+	HaltDate       string `xml:"HaltDate"`
+	HaltTime       string `xml:"HaltTime"`
+	ResumptionDate string `xml:"ResumptionDate"`
+	ResumptionTime string `xml:"ResumptionQuoteTime"`
+}
+
+func (s *NasdaqSource) Fetch() ([]TradeHalt, *time.Time, error) {
+	resp, err := conditionalGet(s.Client, nasdaqTradeHaltURL, s.UserAgent, s.etag, s.lastModified)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch nasdaq trade halts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return s.cachedHalts, &s.lastModified, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	var feed nasdaqRSS
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse nasdaq rss: %w", err)
+	}
+
+	var halts []TradeHalt
+	for _, item := range feed.Channel.Items {
+		var haltDateTime time.Time
+		if item.HaltDate != "" && item.HaltTime != "" {
+			haltDateTime, err = time.ParseInLocation("01/02/2006 15:04:05", item.HaltDate+" "+item.HaltTime, nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse nasdaq halt datetime", "symbol", item.Symbol, "error", err)
+			}
+		}
+
+		var resumeDateTime time.Time
+		if item.ResumptionDate != "" && item.ResumptionTime != "" {
+			resumeDateTime, err = time.ParseInLocation("01/02/2006 15:04:05", item.ResumptionDate+" "+item.ResumptionTime, nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse nasdaq resume datetime", "symbol", item.Symbol, "error", err)
+			}
+		}
+
+		halts = append(halts, TradeHalt{
+			Symbol:           item.Symbol,
+			Name:             item.Name,
+			Exchange:         "NASDAQ",
+			Reason:           item.ReasonCode,
+			NormalizedReason: normalizeNasdaqReason(item.ReasonCode),
+			HaltDateTime:     haltDateTime,
+			ResumeDateTime:   resumeDateTime,
+		})
+	}
+
+	if feed.Channel.LastBuildDate != "" {
+		t, err := time.Parse(time.RFC1123, feed.Channel.LastBuildDate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse nasdaq lastBuildDate: %w", err)
+		}
+		s.lastModified = t
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.cachedHalts = halts
+
+	return halts, &s.lastModified, nil
+}
+
+func normalizeNasdaqReason(code string) HaltReason {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "LUDP", "LUDS", "MWC1", "MWC2", "MWC3":
+		return ReasonVolatility
+	case "T1", "T2", "T6", "T8", "T12":
+		return ReasonNewsPending
+	case "T3":
+		return ReasonNewsReleased
+	case "H4", "H9", "H10", "H11":
+		return ReasonRegulatory
+	default:
+		return ReasonOther
+	}
+}
+
+// OTCSource fetches trade halts from FINRA's OTC halts feed.
+type OTCSource struct {
+	Client    *http.Client
+	UserAgent string
+
+	etag         string
+	lastModified time.Time
+	cachedHalts  []TradeHalt
+}
+
+func (s *OTCSource) Name() string { return "otc" }
+
+type otcHalt struct {
+	Symbol     string `json:"symbol"`
+	Name       string `json:"securityName"`
+	ReasonCode string `json:"haltReasonCode"`
+	HaltTime   string `json:"haltTime"`
+	ResumeTime string `json:"resumeTime"`
+}
+
+func (s *OTCSource) Fetch() ([]TradeHalt, *time.Time, error) {
+	resp, err := conditionalGet(s.Client, otcTradeHaltURL, s.UserAgent, s.etag, s.lastModified)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch otc trade halts: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return s.cachedHalts, &s.lastModified, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	var raw []otcHalt
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse otc halts: %w", err)
+	}
+
+	var halts []TradeHalt
+	for _, h := range raw {
+		var haltDateTime, resumeDateTime time.Time
+		if h.HaltTime != "" {
+			haltDateTime, err = time.ParseInLocation(time.RFC3339, h.HaltTime, nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse otc halt datetime", "symbol", h.Symbol, "error", err)
+			}
+		}
+		if h.ResumeTime != "" {
+			resumeDateTime, err = time.ParseInLocation(time.RFC3339, h.ResumeTime, nyseLocation)
+			if err != nil {
+				slogLogger.Warn("failed to parse otc resume datetime", "symbol", h.Symbol, "error", err)
+			}
+		}
+
+		halts = append(halts, TradeHalt{
+			Symbol:           h.Symbol,
+			Name:             h.Name,
+			Exchange:         "OTC",
+			Reason:           h.ReasonCode,
+			NormalizedReason: normalizeOTCReason(h.ReasonCode),
+			HaltDateTime:     haltDateTime,
+			ResumeDateTime:   resumeDateTime,
+		})
+	}
+
+	if lm := resp.Header.Get("Last-Modified"); lm != "" {
+		t, err := time.Parse(time.RFC1123, lm)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse otc last modified header: %w", err)
+		}
+		s.lastModified = t
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.cachedHalts = halts
+
+	return halts, &s.lastModified, nil
+}
+
+func normalizeOTCReason(code string) HaltReason {
+	switch strings.ToUpper(strings.TrimSpace(code)) {
+	case "T1", "T2", "T6", "T8", "T12":
+		return ReasonNewsPending
+	case "T3":
+		return ReasonNewsReleased
+	case "D", "H10", "H11":
+		return ReasonRegulatory
+	default:
+		return ReasonOther
+	}
+}
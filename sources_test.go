@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNormalizeNYSEReason(t *testing.T) {
+	cases := map[string]HaltReason{
+		"LUDP": ReasonVolatility,
+		"ludp": ReasonVolatility,
+		"T1":   ReasonNewsPending,
+		"T3":   ReasonNewsReleased,
+		"H10":  ReasonRegulatory,
+		"XYZ":  ReasonOther,
+		"":     ReasonOther,
+	}
+
+	for code, want := range cases {
+		if got := normalizeNYSEReason(code); got != want {
+			t.Errorf("normalizeNYSEReason(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestNormalizeNasdaqReason(t *testing.T) {
+	cases := map[string]HaltReason{
+		"MWC1": ReasonVolatility,
+		"T2":   ReasonNewsPending,
+		"T3":   ReasonNewsReleased,
+		"H4":   ReasonRegulatory,
+		"ZZZ":  ReasonOther,
+	}
+
+	for code, want := range cases {
+		if got := normalizeNasdaqReason(code); got != want {
+			t.Errorf("normalizeNasdaqReason(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestNormalizeOTCReason(t *testing.T) {
+	cases := map[string]HaltReason{
+		"T1":  ReasonNewsPending,
+		"T3":  ReasonNewsReleased,
+		"D":   ReasonRegulatory,
+		"ZZZ": ReasonOther,
+	}
+
+	for code, want := range cases {
+		if got := normalizeOTCReason(code); got != want {
+			t.Errorf("normalizeOTCReason(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+// fakeHaltSource is a HaltSource that returns a canned result, for testing
+// fetchFromSources without hitting the network.
+type fakeHaltSource struct {
+	name         string
+	halts        []TradeHalt
+	lastModified *time.Time
+	err          error
+}
+
+func (f *fakeHaltSource) Name() string { return f.name }
+
+func (f *fakeHaltSource) Fetch() ([]TradeHalt, *time.Time, error) {
+	return f.halts, f.lastModified, f.err
+}
+
+func TestFetchFromSourcesDedupesBySymbolAndHaltTime(t *testing.T) {
+	// Reproduce the same instant as it actually arrives from two different
+	// sources: NYSE/Nasdaq parse a wall-clock timestamp against
+	// nyseLocation, while OTC parses an RFC3339 string carrying its own
+	// explicit offset. The results are Equal() but have different
+	// Locations, so the dedup key must be based on the instant rather than
+	// time.Time.String().
+	nyseHaltTime, err := time.ParseInLocation("2006-01-02 15:04:05", "2024-01-02 09:30:00", nyseLocation)
+	if err != nil {
+		t.Fatalf("ParseInLocation() error = %v", err)
+	}
+	otcHaltTime, err := time.Parse(time.RFC3339, nyseHaltTime.UTC().Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !nyseHaltTime.Equal(otcHaltTime) {
+		t.Fatalf("fixture bug: nyseHaltTime %v and otcHaltTime %v are not the same instant", nyseHaltTime, otcHaltTime)
+	}
+
+	older := nyseHaltTime.Add(-time.Hour)
+	newer := nyseHaltTime.Add(time.Hour)
+
+	nyse := &fakeHaltSource{
+		name: "nyse",
+		halts: []TradeHalt{
+			{Symbol: "ABC", Exchange: "NYSE", HaltDateTime: nyseHaltTime},
+			{Symbol: "DEF", Exchange: "NYSE", HaltDateTime: nyseHaltTime},
+		},
+		lastModified: &older,
+	}
+	otc := &fakeHaltSource{
+		name: "otc",
+		halts: []TradeHalt{
+			// Same (symbol, instant) as an NYSE halt above, but parsed via
+			// the OTC/RFC3339 path - should be deduped, keeping only one.
+			{Symbol: "ABC", Exchange: "OTC", HaltDateTime: otcHaltTime},
+			{Symbol: "GHI", Exchange: "OTC", HaltDateTime: otcHaltTime},
+		},
+		lastModified: &newer,
+	}
+
+	halts, lastModified, err := fetchFromSources([]HaltSource{nyse, otc})
+	if err != nil {
+		t.Fatalf("fetchFromSources() error = %v", err)
+	}
+
+	if len(halts) != 3 {
+		t.Fatalf("len(halts) = %d, want 3 (got %+v)", len(halts), halts)
+	}
+
+	if lastModified == nil || !lastModified.Equal(newer) {
+		t.Errorf("lastModified = %v, want %v", lastModified, newer)
+	}
+}
+
+func TestFetchFromSourcesPropagatesError(t *testing.T) {
+	failing := &fakeHaltSource{name: "nyse", err: fmt.Errorf("boom")}
+
+	if _, _, err := fetchFromSources([]HaltSource{failing}); err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+}